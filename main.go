@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rancher/wrangler/v3/pkg/kubeconfig"
+	"github.com/rancher/wrangler/v3/pkg/signals"
+	"github.com/rancher/wrangler/v3/pkg/start"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend/iscsi"
+	_ "github.com/harvester/networkfs-manager/pkg/backend/nfs"
+	"github.com/harvester/networkfs-manager/pkg/backend/smb"
+	"github.com/harvester/networkfs-manager/pkg/client"
+	"github.com/harvester/networkfs-manager/pkg/controller/endpoint"
+	"github.com/harvester/networkfs-manager/pkg/controller/endpointslice"
+	ctlcontrollers "github.com/harvester/networkfs-manager/pkg/generated/controllers"
+	"github.com/harvester/networkfs-manager/pkg/utils"
+)
+
+const (
+	endpointSourceEndpoints     = "endpoints"
+	endpointSourceEndpointSlice = "endpointslices"
+	endpointSourceAuto          = "auto"
+
+	discoveryAPIGroupVersion = "discovery.k8s.io/v1"
+)
+
+var (
+	kubeConfig     string
+	namespace      string
+	nodeName       string
+	endpointSource string
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "networkfs-manager"
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "kubeconfig",
+			EnvVars:     []string{"KUBECONFIG"},
+			Destination: &kubeConfig,
+		},
+		&cli.StringFlag{
+			Name:        "namespace",
+			EnvVars:     []string{"NAMESPACE"},
+			Destination: &namespace,
+			Value:       "longhorn-system",
+		},
+		&cli.StringFlag{
+			Name:        "node-name",
+			EnvVars:     []string{"NODE_NAME"},
+			Destination: &nodeName,
+		},
+		&cli.StringFlag{
+			Name:    "endpoint-source",
+			Usage:   "which API to watch for endpoint changes: endpoints, endpointslices, or auto",
+			EnvVars: []string{"ENDPOINT_SOURCE"},
+			Value:   endpointSourceAuto,
+		},
+	}
+	app.Action = run
+	app.Commands = []*cli.Command{waitCommand()}
+
+	if err := app.Run(os.Args); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// waitCommand implements `networkfs-manager cli wait <name>`, blocking until
+// the named NetworkFilesystem reaches --target-state or the --timeout
+// elapses, so scripts and Harvester's VM-import/backup flows can
+// synchronously provision a share before mounting it.
+func waitCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cli",
+		Usage: "operator helpers for scripting against NetworkFilesystem resources",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "wait",
+				Usage:     "block until a NetworkFilesystem reaches the target state",
+				ArgsUsage: "NAME",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "kubeconfig",
+						EnvVars:     []string{"KUBECONFIG"},
+						Destination: &kubeConfig,
+					},
+					&cli.StringFlag{
+						Name:        "namespace",
+						EnvVars:     []string{"NAMESPACE"},
+						Value:       "longhorn-system",
+						Destination: &namespace,
+					},
+					&cli.StringFlag{
+						Name:  "target-state",
+						Usage: "NetworkFSState to wait for (the endpoint controllers only ever publish Enabling once a backend endpoint is ready; Enabled is set by an external component)",
+						Value: string(networkfsv1.NetworkFSStateEnabling),
+					},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "how long to wait before giving up",
+						Value: 30 * time.Minute,
+					},
+				},
+				Action: runWait,
+			},
+		},
+	}
+}
+
+func runWait(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one argument: the NetworkFilesystem name")
+	}
+	name := c.Args().First()
+	target := networkfsv1.NetworkFSState(c.String("target-state"))
+
+	cfg, err := kubeconfig.GetNonInteractiveClientConfig(kubeConfig).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+
+	controllers, err := ctlcontrollers.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to build controller factory: %w", err)
+	}
+
+	if err := client.WaitFor(c.Context, controllers.NetworkFS.NetworkFilesystems(), namespace, name, target, c.Duration("timeout")); err != nil {
+		return err
+	}
+
+	logrus.Infof("networkfilesystem %s reached state %s", name, target)
+	return nil
+}
+
+func run(c *cli.Context) error {
+	endpointSource = c.String("endpoint-source")
+	ctx := signals.SetupSignalContext()
+
+	cfg, err := kubeconfig.GetNonInteractiveClientConfig(kubeConfig).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to build kubeconfig: %w", err)
+	}
+
+	resolvedSource, err := resolveEndpointSource(cfg, endpointSource)
+	if err != nil {
+		return fmt.Errorf("failed to resolve endpoint source: %w", err)
+	}
+
+	controllers, err := ctlcontrollers.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to build controller factory: %w", err)
+	}
+
+	opt := &utils.Option{
+		Namespace: namespace,
+		NodeName:  nodeName,
+	}
+
+	// nfs registers itself via init(); smb and iscsi need live client caches
+	// to resolve their service/secret references.
+	smb.New(controllers.Core.Service().Cache(), controllers.Core.Secret().Cache())
+	iscsi.New(controllers.Core.Service().Cache())
+
+	switch resolvedSource {
+	case endpointSourceEndpoints:
+		if err := endpoint.Register(ctx, controllers.Core.Endpoints(), controllers.NetworkFS.NetworkFilesystems(), controllers.Core.Service(), opt); err != nil {
+			return fmt.Errorf("failed to register endpoint controller: %w", err)
+		}
+	case endpointSourceEndpointSlice:
+		if err := endpointslice.Register(ctx, controllers.Discovery.EndpointSlice(), controllers.Core.Node(), controllers.NetworkFS.NetworkFilesystems(), controllers.Core.Service(), opt); err != nil {
+			return fmt.Errorf("failed to register endpointslice controller: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown endpoint source %q", resolvedSource)
+	}
+
+	logrus.Infof("Starting networkfs-manager with endpoint source %q", resolvedSource)
+	return start.All(ctx, 2, controllers)
+}
+
+// resolveEndpointSource turns "auto" into a concrete source by probing whether the
+// cluster serves discovery.k8s.io/v1, falling back to the legacy Endpoints API on
+// older clusters that don't.
+func resolveEndpointSource(cfg *rest.Config, source string) (string, error) {
+	if source != endpointSourceAuto {
+		return source, nil
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return endpointSourceEndpoints, nil
+	}
+
+	if _, err := dc.ServerResourcesForGroupVersion(discoveryAPIGroupVersion); err != nil {
+		logrus.Infof("Cluster does not serve %s, falling back to endpoints", discoveryAPIGroupVersion)
+		return endpointSourceEndpoints, nil
+	}
+
+	return endpointSourceEndpointSlice, nil
+}
@@ -0,0 +1,49 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the API group NetworkFilesystem belongs to.
+const GroupName = "harvesterhci.io"
+
+// SchemeGroupVersion is the harvesterhci.io/v1beta1 group-version.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1beta1"}
+
+// Scheme is the runtime.Scheme the generated client and controllers encode
+// and decode NetworkFilesystem resources against.
+var Scheme = runtime.NewScheme()
+
+var (
+	// SchemeBuilder collects the functions that add types to Scheme.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+	// AddToScheme applies SchemeBuilder to a runtime.Scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+
+	// ParameterCodec converts list/get options to and from URL values for
+	// requests against the NetworkFilesystem resource.
+	ParameterCodec = runtime.NewParameterCodec(Scheme)
+)
+
+func init() {
+	if err := AddToScheme(Scheme); err != nil {
+		panic(err)
+	}
+}
+
+// Resource returns a GroupResource for the given resource name in this
+// group-version.
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+func addKnownTypes(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&NetworkFilesystem{},
+		&NetworkFilesystemList{},
+	)
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
@@ -0,0 +1,188 @@
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkFilesystem represents a Longhorn-backed (or other protocol-backed)
+// share that Harvester VMs mount, tracking the discovered endpoint(s) of its
+// backing workload in Status.
+type NetworkFilesystem struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkFSSpec   `json:"spec"`
+	Status NetworkFSStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NetworkFilesystemList is a list of NetworkFilesystem resources.
+type NetworkFilesystemList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NetworkFilesystem `json:"items"`
+}
+
+// NetworkFSType identifies the protocol a NetworkFilesystem is exported
+// over, and selects the backend.Provider used to resolve its endpoint.
+type NetworkFSType string
+
+const (
+	NetworkFSTypeNFS   NetworkFSType = "nfs"
+	NetworkFSTypeSMB   NetworkFSType = "smb"
+	NetworkFSTypeISCSI NetworkFSType = "iscsi"
+)
+
+// NetworkFSState is the desired/observed lifecycle state of a
+// NetworkFilesystem.
+type NetworkFSState string
+
+const (
+	// NetworkFSStateEnabled is a terminal state driven by an external
+	// component (not this manager); the endpoint controllers never set it
+	// themselves. They always publish NetworkFSStateEnabling, with Status
+	// Ready once a backend endpoint is discovered and healthy and NotReady
+	// while no ready address exists.
+	NetworkFSStateEnabled  NetworkFSState = "Enabled"
+	NetworkFSStateDisabled NetworkFSState = "Disabled"
+	NetworkFSStateEnabling NetworkFSState = "Enabling"
+)
+
+// EndpointStatus reports whether the backing workload currently has a ready
+// address.
+type EndpointStatus string
+
+const (
+	EndpointStatusReady    EndpointStatus = "Ready"
+	EndpointStatusNotReady EndpointStatus = "NotReady"
+)
+
+// TopologyPolicy controls whether the endpoint controller picks a
+// per-node endpoint address based on EndpointSlice zone hints.
+type TopologyPolicy string
+
+const (
+	// TopologyPolicyAuto prefers a same-zone address, falls back to
+	// same-region, then to any ready address.
+	TopologyPolicyAuto TopologyPolicy = "Auto"
+	// TopologyPolicyPreferLocal behaves like Auto; it exists as a distinct
+	// value so operators can express intent even though the fallback chain
+	// is currently identical.
+	TopologyPolicyPreferLocal TopologyPolicy = "PreferLocal"
+	// TopologyPolicyStrict only assigns a node an address located in, or
+	// hinted for, its own zone, leaving the node unset rather than crossing
+	// zones.
+	TopologyPolicyStrict TopologyPolicy = "Strict"
+	// TopologyPolicyNone disables per-node selection; Status.Endpoint is
+	// the only address published.
+	TopologyPolicyNone TopologyPolicy = "None"
+)
+
+// NetworkFSConditionType enumerates the condition types recorded in
+// NetworkFSStatus.NetworkFSConds.
+type NetworkFSConditionType string
+
+const (
+	ConditionTypeReady           NetworkFSConditionType = "Ready"
+	ConditionTypeNotReady        NetworkFSConditionType = "NotReady"
+	ConditionTypeEndpointChanged NetworkFSConditionType = "EndpointChanged"
+)
+
+// NetworkFSCondition is a single observation of a NetworkFilesystem's state.
+type NetworkFSCondition struct {
+	Type               NetworkFSConditionType `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
+
+// SMBSpec configures the CIFS/SMB share backend.Provider resolves the
+// endpoint from.
+type SMBSpec struct {
+	// ServiceName is the Service fronting the SMB share.
+	ServiceName string `json:"serviceName"`
+	// Port defaults to 445 when unset.
+	Port int32 `json:"port,omitempty"`
+	// CredentialsSecretRef names a Secret in the same namespace holding the
+	// SMB username/password.
+	CredentialsSecretRef string `json:"credentialsSecretRef,omitempty"`
+}
+
+// ISCSISpec configures the iSCSI block-mode backend.Provider resolves the
+// target from.
+type ISCSISpec struct {
+	// ServiceName is the Service whose ClusterIP is advertised as the
+	// iSCSI portal address.
+	ServiceName string `json:"serviceName"`
+	// Port defaults to 3260 when unset.
+	Port int32 `json:"port,omitempty"`
+	// TargetIQN is the iSCSI Qualified Name of the exported target.
+	TargetIQN string `json:"targetIQN"`
+}
+
+// NetworkFSSpec is the desired state of a NetworkFilesystem.
+type NetworkFSSpec struct {
+	DesiredState NetworkFSState `json:"desiredState"`
+	// Type selects the backend.Provider used to resolve this
+	// NetworkFilesystem's endpoint. Defaults to nfs.
+	Type NetworkFSType `json:"type,omitempty"`
+	// TopologyPolicy controls per-node endpoint selection. Defaults to
+	// None (no per-node selection).
+	TopologyPolicy TopologyPolicy `json:"topologyPolicy,omitempty"`
+	SMB            *SMBSpec       `json:"smb,omitempty"`
+	ISCSI          *ISCSISpec     `json:"iscsi,omitempty"`
+}
+
+// EndpointAddress is a single ready address backing a NetworkFilesystem,
+// aggregated from either the legacy Endpoints or the EndpointSlice
+// endpoint controller.
+type EndpointAddress struct {
+	IP       string `json:"ip"`
+	Port     int32  `json:"port,omitempty"`
+	Ready    bool   `json:"ready"`
+	NodeName string `json:"nodeName,omitempty"`
+	Zone     string `json:"zone,omitempty"`
+}
+
+// BackendEndpoint is the protocol-specific location backend.Provider
+// resolved for this NetworkFilesystem. NFS and SMB populate Address/Port;
+// iSCSI, which has no IP+port mount target, populates IQN/Portal instead.
+type BackendEndpoint struct {
+	Address string `json:"address,omitempty"`
+	Port    int32  `json:"port,omitempty"`
+	IQN     string `json:"iqn,omitempty"`
+	Portal  string `json:"portal,omitempty"`
+}
+
+// NetworkFSStatus is the observed state of a NetworkFilesystem.
+type NetworkFSStatus struct {
+	State  NetworkFSState `json:"state,omitempty"`
+	Status EndpointStatus `json:"status,omitempty"`
+	Type   NetworkFSType  `json:"type,omitempty"`
+
+	// Endpoint is the primary address, kept for clients that only mount a
+	// single IP. It is always the first entry of Endpoints.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Endpoints lists every ready address currently backing this
+	// NetworkFilesystem.
+	Endpoints []EndpointAddress `json:"endpoints,omitempty"`
+	// PerNodeEndpoints maps a node name to the address selected for it by
+	// Spec.TopologyPolicy. Unset unless TopologyPolicy is set to a value
+	// other than None.
+	PerNodeEndpoints map[string]string `json:"perNodeEndpoints,omitempty"`
+	// BackendEndpoint is the backend.Provider-resolved location for
+	// Spec.Type, refreshed alongside Endpoints on every reconcile. For NFS
+	// this duplicates Endpoint/Endpoints' primary address; for SMB it is the
+	// ClusterIP of Spec.SMB.ServiceName; for iSCSI it is the only place the
+	// target IQN/portal is published.
+	BackendEndpoint *BackendEndpoint `json:"backendEndpoint,omitempty"`
+
+	NetworkFSConds []NetworkFSCondition `json:"conditions,omitempty"`
+}
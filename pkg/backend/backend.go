@@ -0,0 +1,48 @@
+// Package backend abstracts the protocol a NetworkFilesystem is exported
+// over. The endpoint controllers dispatch on NetworkFilesystem.Spec.Type to
+// pick a Provider instead of assuming a Longhorn share-manager NFS export.
+package backend
+
+import (
+	"context"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+// Endpoint is the resolved, protocol-specific location a NetworkFilesystem
+// should be mounted from. Not every field applies to every backend: NFS and
+// SMB populate Address/Port, iSCSI populates IQN/Portal instead.
+type Endpoint struct {
+	Address string
+	Port    int32
+	IQN     string
+	Portal  string
+}
+
+// Provider resolves and health-checks the protocol-specific backend a
+// NetworkFilesystem is exported over (Longhorn share-manager NFS, CIFS,
+// iSCSI, ...).
+type Provider interface {
+	// Type reports the networkfsv1.NetworkFSType this provider serves.
+	Type() networkfsv1.NetworkFSType
+	// DiscoverEndpoint resolves the share's current endpoint from the
+	// NetworkFilesystem spec/status.
+	DiscoverEndpoint(ctx context.Context, nfs *networkfsv1.NetworkFilesystem) (Endpoint, error)
+	// HealthCheck reports whether a previously discovered endpoint is
+	// still reachable.
+	HealthCheck(ctx context.Context, endpoint Endpoint) error
+}
+
+var providers = map[networkfsv1.NetworkFSType]Provider{}
+
+// Register adds a Provider to the global registry, keyed by the
+// NetworkFSType it serves.
+func Register(p Provider) {
+	providers[p.Type()] = p
+}
+
+// Get looks up the Provider registered for the given NetworkFSType.
+func Get(t networkfsv1.NetworkFSType) (Provider, bool) {
+	p, ok := providers[t]
+	return p, ok
+}
@@ -0,0 +1,60 @@
+// Package iscsi implements a backend.Provider for block-mode network
+// volumes exported over iSCSI, pairing a target IQN with the portal address
+// of its backing service.
+package iscsi
+
+import (
+	"context"
+	"fmt"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend"
+)
+
+const defaultPort = 3260
+
+// Provider resolves an iSCSI target IQN and portal from
+// NetworkFilesystem.Spec.ISCSI.
+type Provider struct {
+	ServiceCache ctlcorev1.ServiceCache
+}
+
+// New builds an iSCSI Provider and registers it with the backend registry.
+func New(serviceCache ctlcorev1.ServiceCache) *Provider {
+	p := &Provider{ServiceCache: serviceCache}
+	backend.Register(p)
+	return p
+}
+
+func (p *Provider) Type() networkfsv1.NetworkFSType {
+	return networkfsv1.NetworkFSTypeISCSI
+}
+
+func (p *Provider) DiscoverEndpoint(_ context.Context, nfs *networkfsv1.NetworkFilesystem) (backend.Endpoint, error) {
+	if nfs.Spec.ISCSI == nil || nfs.Spec.ISCSI.TargetIQN == "" {
+		return backend.Endpoint{}, fmt.Errorf("networkfilesystem %s has no spec.iscsi.targetIQN configured", nfs.Name)
+	}
+
+	svc, err := p.ServiceCache.Get(nfs.Namespace, nfs.Spec.ISCSI.ServiceName)
+	if err != nil {
+		return backend.Endpoint{}, fmt.Errorf("failed to resolve iscsi service %s: %w", nfs.Spec.ISCSI.ServiceName, err)
+	}
+
+	port := nfs.Spec.ISCSI.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	return backend.Endpoint{
+		IQN:    nfs.Spec.ISCSI.TargetIQN,
+		Portal: fmt.Sprintf("%s:%d", svc.Spec.ClusterIP, port),
+	}, nil
+}
+
+func (p *Provider) HealthCheck(_ context.Context, endpoint backend.Endpoint) error {
+	if endpoint.IQN == "" || endpoint.Portal == "" {
+		return fmt.Errorf("iscsi endpoint is missing iqn or portal")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Package nfs is the default backend.Provider, serving the Longhorn
+// share-manager NFS export that networkfs-manager originally assumed for
+// every NetworkFilesystem.
+package nfs
+
+import (
+	"context"
+	"fmt"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend"
+)
+
+func init() {
+	backend.Register(&Provider{})
+}
+
+// Provider resolves the Longhorn share-manager NFS export backing a
+// NetworkFilesystem from the addresses the endpoint controller already
+// aggregated into Status.Endpoints.
+type Provider struct{}
+
+func (p *Provider) Type() networkfsv1.NetworkFSType {
+	return networkfsv1.NetworkFSTypeNFS
+}
+
+func (p *Provider) DiscoverEndpoint(_ context.Context, nfs *networkfsv1.NetworkFilesystem) (backend.Endpoint, error) {
+	if len(nfs.Status.Endpoints) == 0 {
+		return backend.Endpoint{}, fmt.Errorf("networkfilesystem %s has no ready endpoints", nfs.Name)
+	}
+	primary := nfs.Status.Endpoints[0]
+	return backend.Endpoint{Address: primary.IP, Port: primary.Port}, nil
+}
+
+func (p *Provider) HealthCheck(_ context.Context, endpoint backend.Endpoint) error {
+	if endpoint.Address == "" {
+		return fmt.Errorf("nfs endpoint has no address")
+	}
+	return nil
+}
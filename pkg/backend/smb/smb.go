@@ -0,0 +1,64 @@
+// Package smb implements a backend.Provider for CIFS/SMB shares, letting
+// Harvester VMs mount Windows-friendly network filesystems instead of the
+// default NFS export.
+package smb
+
+import (
+	"context"
+	"fmt"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend"
+)
+
+const defaultPort = 445
+
+// Provider resolves a CIFS share endpoint from the service name/port and
+// credentials Secret referenced on NetworkFilesystem.Spec.SMB.
+type Provider struct {
+	ServiceCache ctlcorev1.ServiceCache
+	SecretCache  ctlcorev1.SecretCache
+}
+
+// New builds an SMB Provider and registers it with the backend registry.
+func New(serviceCache ctlcorev1.ServiceCache, secretCache ctlcorev1.SecretCache) *Provider {
+	p := &Provider{ServiceCache: serviceCache, SecretCache: secretCache}
+	backend.Register(p)
+	return p
+}
+
+func (p *Provider) Type() networkfsv1.NetworkFSType {
+	return networkfsv1.NetworkFSTypeSMB
+}
+
+func (p *Provider) DiscoverEndpoint(_ context.Context, nfs *networkfsv1.NetworkFilesystem) (backend.Endpoint, error) {
+	if nfs.Spec.SMB == nil || nfs.Spec.SMB.ServiceName == "" {
+		return backend.Endpoint{}, fmt.Errorf("networkfilesystem %s has no spec.smb.serviceName configured", nfs.Name)
+	}
+
+	svc, err := p.ServiceCache.Get(nfs.Namespace, nfs.Spec.SMB.ServiceName)
+	if err != nil {
+		return backend.Endpoint{}, fmt.Errorf("failed to resolve smb service %s: %w", nfs.Spec.SMB.ServiceName, err)
+	}
+
+	if nfs.Spec.SMB.CredentialsSecretRef != "" {
+		if _, err := p.SecretCache.Get(nfs.Namespace, nfs.Spec.SMB.CredentialsSecretRef); err != nil {
+			return backend.Endpoint{}, fmt.Errorf("failed to resolve smb credentials secret %s: %w", nfs.Spec.SMB.CredentialsSecretRef, err)
+		}
+	}
+
+	port := nfs.Spec.SMB.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	return backend.Endpoint{Address: svc.Spec.ClusterIP, Port: port}, nil
+}
+
+func (p *Provider) HealthCheck(_ context.Context, endpoint backend.Endpoint) error {
+	if endpoint.Address == "" {
+		return fmt.Errorf("smb endpoint has no address")
+	}
+	return nil
+}
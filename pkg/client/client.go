@@ -0,0 +1,113 @@
+// Package client provides a small library on top of the generated
+// NetworkFilesystem client for callers (the `cli wait` subcommand,
+// Harvester's VM-import and backup flows) that need to synchronously wait
+// for a state transition instead of racing the endpoint controller.
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	ctlntefsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
+)
+
+// TimeoutError is returned by WaitFor when the target state isn't observed
+// before the deadline. It carries the last NetworkFSCondition seen so
+// callers can report a reason without an extra Get.
+type TimeoutError struct {
+	Name          string
+	Target        networkfsv1.NetworkFSState
+	LastCondition *networkfsv1.NetworkFSCondition
+}
+
+func (e *TimeoutError) Error() string {
+	if e.LastCondition == nil {
+		return fmt.Sprintf("timed out waiting for networkfilesystem %s to reach state %s", e.Name, e.Target)
+	}
+	return fmt.Sprintf("timed out waiting for networkfilesystem %s to reach state %s, last condition: %s (%s)",
+		e.Name, e.Target, e.LastCondition.Type, e.LastCondition.Message)
+}
+
+// WaitFor blocks until the named NetworkFilesystem's Status.State equals
+// target and Status.Status is EndpointStatusReady, or returns a *TimeoutError
+// once timeout elapses. It watches rather than polls so it returns as soon
+// as the endpoint controller publishes the transition, re-establishing the
+// watch from the last observed resourceVersion whenever the server closes
+// it, since that routinely happens (apiserver restart, watch timeout, LB
+// rebalance) well before a caller's real deadline.
+func WaitFor(ctx context.Context, networkfilesystems ctlntefsv1.NetworkFilesystemController, namespace, name string, target networkfsv1.NetworkFSState, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	existing, err := networkfilesystems.Get(namespace, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get networkfilesystem %s: %w", name, err)
+	}
+	if reached(existing, target) {
+		return nil
+	}
+
+	resourceVersion := existing.ResourceVersion
+	var lastCond *networkfsv1.NetworkFSCondition
+
+	for {
+		watcher, err := networkfilesystems.Watch(namespace, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch networkfilesystem %s: %w", name, err)
+		}
+
+		reachedTarget, timedOut := drainWatch(ctx, watcher, target, &resourceVersion, &lastCond)
+		watcher.Stop()
+		if timedOut {
+			return &TimeoutError{Name: name, Target: target, LastCondition: lastCond}
+		}
+		if reachedTarget {
+			return nil
+		}
+		// watcher's channel closed with ctx still open: the server dropped
+		// the watch (apiserver restart, watch timeout, LB rebalance) rather
+		// than the deadline elapsing. Re-watch from resourceVersion instead
+		// of treating the close as a failure.
+	}
+}
+
+// drainWatch consumes watcher's ResultChan until ctx is done (reachedTarget,
+// timedOut = false, true) or the target state is reached (true, false). A
+// closed channel with ctx still open returns (false, false), leaving it to
+// the caller to re-watch from the updated resourceVersion.
+func drainWatch(ctx context.Context, watcher watch.Interface, target networkfsv1.NetworkFSState, resourceVersion *string, lastCond **networkfsv1.NetworkFSCondition) (reachedTarget, timedOut bool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, true
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, false
+			}
+			nfs, ok := event.Object.(*networkfsv1.NetworkFilesystem)
+			if !ok {
+				continue
+			}
+			*resourceVersion = nfs.ResourceVersion
+			if len(nfs.Status.NetworkFSConds) > 0 {
+				*lastCond = &nfs.Status.NetworkFSConds[len(nfs.Status.NetworkFSConds)-1]
+			}
+			if reached(nfs, target) {
+				return true, false
+			}
+		}
+	}
+}
+
+func reached(nfs *networkfsv1.NetworkFilesystem, target networkfsv1.NetworkFSState) bool {
+	return nfs.Status.State == target && nfs.Status.Status == networkfsv1.EndpointStatusReady
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	ctlntefsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
+)
+
+// fakeNetworkFilesystemController implements ctlntefsv1.NetworkFilesystemController
+// with just enough behavior for WaitFor: Get returns the current object, and
+// Watch hands back one watcher per call from watchers, in order, so a test
+// can make an earlier watcher's channel close without ctx being done to
+// exercise the re-watch-on-close path.
+type fakeNetworkFilesystemController struct {
+	ctlntefsv1.NetworkFilesystemController
+
+	initial  *networkfsv1.NetworkFilesystem
+	watchers []*watch.FakeWatcher
+	watchCnt int
+}
+
+func (f *fakeNetworkFilesystemController) Get(_, _ string, _ metav1.GetOptions) (*networkfsv1.NetworkFilesystem, error) {
+	return f.initial, nil
+}
+
+func (f *fakeNetworkFilesystemController) Watch(_ string, _ metav1.ListOptions) (watch.Interface, error) {
+	if f.watchCnt >= len(f.watchers) {
+		return nil, errors.New("no more fake watchers configured")
+	}
+	w := f.watchers[f.watchCnt]
+	f.watchCnt++
+	return w, nil
+}
+
+func readyNFS(state networkfsv1.NetworkFSState, status networkfsv1.EndpointStatus) *networkfsv1.NetworkFilesystem {
+	return &networkfsv1.NetworkFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-test", ResourceVersion: "1"},
+		Status: networkfsv1.NetworkFSStatus{
+			State:  state,
+			Status: status,
+		},
+	}
+}
+
+func TestWaitForReachesTargetImmediately(t *testing.T) {
+	fake := &fakeNetworkFilesystemController{
+		initial: readyNFS(networkfsv1.NetworkFSStateEnabling, networkfsv1.EndpointStatusReady),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := WaitFor(ctx, fake, "longhorn-system", "pvc-test", networkfsv1.NetworkFSStateEnabling, time.Second); err != nil {
+		t.Fatalf("WaitFor() = %v, want nil", err)
+	}
+}
+
+func TestWaitForReWatchesOnChannelClose(t *testing.T) {
+	droppedWatch := watch.NewFake()
+	resumedWatch := watch.NewFake()
+
+	fake := &fakeNetworkFilesystemController{
+		initial:  readyNFS(networkfsv1.NetworkFSStateEnabling, networkfsv1.EndpointStatusNotReady),
+		watchers: []*watch.FakeWatcher{droppedWatch, resumedWatch},
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- WaitFor(context.Background(), fake, "longhorn-system", "pvc-test", networkfsv1.NetworkFSStateEnabling, 5*time.Second)
+	}()
+
+	// The first watch's channel closes (server dropped it) before the
+	// target is ever observed; WaitFor must re-watch rather than fail.
+	droppedWatch.Stop()
+
+	select {
+	case err := <-done:
+		t.Fatalf("WaitFor() returned %v before the re-watch could deliver the target state", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	resumedWatch.Modify(readyNFS(networkfsv1.NetworkFSStateEnabling, networkfsv1.EndpointStatusReady))
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WaitFor() = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("WaitFor() did not return after the re-watch delivered the target state")
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	w := watch.NewFake()
+	fake := &fakeNetworkFilesystemController{
+		initial:  readyNFS(networkfsv1.NetworkFSStateEnabling, networkfsv1.EndpointStatusNotReady),
+		watchers: []*watch.FakeWatcher{w},
+	}
+
+	err := WaitFor(context.Background(), fake, "longhorn-system", "pvc-test", networkfsv1.NetworkFSStateEnabling, 50*time.Millisecond)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("WaitFor() = %v, want a *TimeoutError", err)
+	}
+}
@@ -2,6 +2,7 @@ package endpoint
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend"
 	ctlntefsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
 	"github.com/harvester/networkfs-manager/pkg/utils"
 )
@@ -84,45 +86,75 @@ func (c *Controller) OnEndpointChange(_ string, endpoint *corev1.Endpoints) (*co
 		return nil, nil
 	}
 
+	provider, ok := backend.Get(networkFS.Spec.Type)
+	if !ok {
+		logrus.Warnf("No backend provider registered for type %s on networkfilesystem %s, falling back to %s", networkFS.Spec.Type, networkFS.Name, networkfsv1.NetworkFSTypeNFS)
+		provider, ok = backend.Get(networkfsv1.NetworkFSTypeNFS)
+		if !ok {
+			return nil, fmt.Errorf("no nfs backend provider registered")
+		}
+	}
+
+	readyAddrs := readyAddresses(endpoint)
+
 	networkFSCpy := networkFS.DeepCopy()
-	if len(endpoint.Subsets) == 0 || len(endpoint.Subsets[0].Addresses) == 0 {
+	if len(readyAddrs) == 0 {
 		networkFSCpy.Status.Endpoint = ""
+		networkFSCpy.Status.Endpoints = nil
+		networkFSCpy.Status.BackendEndpoint = nil
 		networkFSCpy.Status.Status = networkfsv1.EndpointStatusNotReady
-		networkFSCpy.Status.Type = networkfsv1.NetworkFSTypeNFS
+		networkFSCpy.Status.Type = provider.Type()
 		networkFSCpy.Status.State = networkfsv1.NetworkFSStateEnabling
 		conds := networkfsv1.NetworkFSCondition{
 			Type:               networkfsv1.ConditionTypeNotReady,
 			Status:             corev1.ConditionTrue,
 			LastTransitionTime: metav1.Now(),
 			Reason:             "Endpoint is not ready",
-			Message:            "Endpoint did not contain the corresponding address",
+			Message:            fmt.Sprintf("%s endpoint did not contain the corresponding address", provider.Type()),
 		}
 		networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
 	} else {
-		if networkFSCpy.Status.Endpoint != endpoint.Subsets[0].Addresses[0].IP {
-			changedMsg := "Endpoint address is initialized with " + endpoint.Subsets[0].Addresses[0].IP
-			if changedMsg != "" {
-				changedMsg = "Endpoint address is changed, previous address is " + networkFSCpy.Status.Endpoint
-			}
+		oldEndpoints := networkFSCpy.Status.Endpoints
+		// Providers (e.g. nfs) resolve their endpoint from Status.Endpoints, so
+		// write the freshly-aggregated addresses before calling DiscoverEndpoint
+		// rather than leaving it to see the stale (possibly empty) status.
+		networkFSCpy.Status.Endpoints = readyAddrs
+		discovered, err := provider.DiscoverEndpoint(context.Background(), networkFSCpy)
+		if err != nil {
+			logrus.Errorf("Failed to discover %s endpoint for networkfilesystem %s: %v", provider.Type(), networkFS.Name, err)
+			return nil, err
+		}
+		if err := provider.HealthCheck(context.Background(), discovered); err != nil {
+			logrus.Errorf("Health check failed for %s endpoint on networkfilesystem %s: %v", provider.Type(), networkFS.Name, err)
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(oldEndpoints, readyAddrs) {
 			conds := networkfsv1.NetworkFSCondition{
 				Type:               networkfsv1.ConditionTypeEndpointChanged,
 				Status:             corev1.ConditionTrue,
 				LastTransitionTime: metav1.Now(),
 				Reason:             "Endpoint is changed",
-				Message:            changedMsg,
+				Message:            utils.ChangedEndpointsMessage(oldEndpoints, readyAddrs),
 			}
 			networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
 		}
-		networkFSCpy.Status.Endpoint = endpoint.Subsets[0].Addresses[0].IP
+		networkFSCpy.Status.Endpoint = readyAddrs[0].IP
+		networkFSCpy.Status.BackendEndpoint = &networkfsv1.BackendEndpoint{
+			Address: discovered.Address,
+			Port:    discovered.Port,
+			IQN:     discovered.IQN,
+			Portal:  discovered.Portal,
+		}
 		networkFSCpy.Status.Status = networkfsv1.EndpointStatusReady
-		networkFSCpy.Status.Type = networkfsv1.NetworkFSTypeNFS
+		networkFSCpy.Status.Type = provider.Type()
 		networkFSCpy.Status.State = networkfsv1.NetworkFSStateEnabling
 		conds := networkfsv1.NetworkFSCondition{
 			Type:               networkfsv1.ConditionTypeReady,
 			Status:             corev1.ConditionTrue,
 			LastTransitionTime: metav1.Now(),
 			Reason:             "Endpoint is ready",
-			Message:            "Endpoint contains the corresponding address",
+			Message:            fmt.Sprintf("%s endpoint contains the corresponding address", provider.Type()),
 		}
 		networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
 	}
@@ -136,3 +168,28 @@ func (c *Controller) OnEndpointChange(_ string, endpoint *corev1.Endpoints) (*co
 
 	return nil, nil
 }
+
+// readyAddresses flattens every ready address across all subsets of an Endpoints
+// resource into the CR-facing EndpointAddress shape.
+func readyAddresses(endpoint *corev1.Endpoints) []networkfsv1.EndpointAddress {
+	var addrs []networkfsv1.EndpointAddress
+	for _, subset := range endpoint.Subsets {
+		var port int32
+		if len(subset.Ports) > 0 {
+			port = subset.Ports[0].Port
+		}
+		for _, addr := range subset.Addresses {
+			nodeName := ""
+			if addr.NodeName != nil {
+				nodeName = *addr.NodeName
+			}
+			addrs = append(addrs, networkfsv1.EndpointAddress{
+				IP:       addr.IP,
+				Port:     port,
+				Ready:    true,
+				NodeName: nodeName,
+			})
+		}
+	}
+	return addrs
+}
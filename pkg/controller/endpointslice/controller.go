@@ -0,0 +1,337 @@
+package endpointslice
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	ctldiscoveryv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/discovery/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/backend"
+	ctlntefsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
+	"github.com/harvester/networkfs-manager/pkg/utils"
+)
+
+// serviceNameLabel is the well-known label EndpointSlices carry to associate
+// them back to the Service (and, for Longhorn share-manager, the pvc-backed
+// NetworkFilesystem) they belong to.
+const (
+	serviceNameLabel              = "kubernetes.io/service-name"
+	netFSEndpointSliceHandlerName = "harvester-netfs-endpointslice-handler"
+	netFSNodeHandlerName          = "harvester-netfs-endpointslice-node-handler"
+)
+
+type Controller struct {
+	namespace string
+	nodeName  string
+
+	EndpointSliceCache ctldiscoveryv1.EndpointSliceCache
+	EndpointSlices     ctldiscoveryv1.EndpointSliceController
+	NodeCache          ctlcorev1.NodeCache
+	Nodes              ctlcorev1.NodeController
+	NetworkFSCache     ctlntefsv1.NetworkFilesystemCache
+	NetworkFilsystems  ctlntefsv1.NetworkFilesystemController
+
+	serviceClient ctlcorev1.ServiceController
+
+	// nodeTopologyMu guards nodeTopology, the last-seen zone/region labels
+	// per node. wrangler's OnChange only ever hands us the new object, so
+	// OnNodeChange needs somewhere to remember the previous labels in order
+	// to tell a real topology move from an unrelated heartbeat/status update.
+	nodeTopologyMu sync.Mutex
+	nodeTopology   map[string]nodeTopology
+}
+
+// nodeTopology is the subset of a Node's topology labels OnNodeChange cares
+// about, cached so it can detect when they actually change.
+type nodeTopology struct {
+	zone   string
+	region string
+}
+
+// Register registers the EndpointSlice based endpoint controller. It is the
+// discoveryv1 counterpart of pkg/controller/endpoint, selected via the
+// manager's --endpoint-source flag, and keeps the same headless-service
+// guard via serviceClient. It also watches Nodes so that topology label
+// changes re-reconcile per-node endpoint selection.
+func Register(ctx context.Context, endpointSlices ctldiscoveryv1.EndpointSliceController, nodes ctlcorev1.NodeController, netfilesystems ctlntefsv1.NetworkFilesystemController, serviceClient ctlcorev1.ServiceController, opt *utils.Option) error {
+	c := &Controller{
+		namespace:          opt.Namespace,
+		nodeName:           opt.NodeName,
+		EndpointSlices:     endpointSlices,
+		EndpointSliceCache: endpointSlices.Cache(),
+		NodeCache:          nodes.Cache(),
+		Nodes:              nodes,
+		NetworkFilsystems:  netfilesystems,
+		NetworkFSCache:     netfilesystems.Cache(),
+		serviceClient:      serviceClient,
+	}
+
+	c.EndpointSlices.OnChange(ctx, netFSEndpointSliceHandlerName, c.OnEndpointSliceChange)
+	c.Nodes.OnChange(ctx, netFSNodeHandlerName, c.OnNodeChange)
+	return nil
+}
+
+// OnNodeChange re-enqueues every watched EndpointSlice when a node's
+// topology labels change, so Status.PerNodeEndpoints picks up zone/region
+// moves and failover across zones stays automatic. The Node informer fires
+// on every update including kubelet heartbeats, so this only acts when the
+// node's zone/region labels actually changed and at least one
+// NetworkFilesystem has topology-aware selection turned on; otherwise every
+// heartbeat would re-reconcile every pvc- NetworkFilesystem in the namespace.
+func (c *Controller) OnNodeChange(_ string, node *corev1.Node) (*corev1.Node, error) {
+	if node == nil || node.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	if !c.topologyChanged(node) {
+		return nil, nil
+	}
+
+	enabled, err := c.anyTopologyPolicyEnabled()
+	if err != nil {
+		logrus.Errorf("Failed to list networkfilesystems to check topology policy on node %s change: %v", node.Name, err)
+		return nil, err
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	slices, err := c.EndpointSliceCache.List(c.namespace, labels.Everything())
+	if err != nil {
+		logrus.Errorf("Failed to list endpointslices to re-reconcile on node %s change: %v", node.Name, err)
+		return nil, err
+	}
+	for _, slice := range slices {
+		if _, ok := slice.Labels[serviceNameLabel]; ok {
+			c.EndpointSlices.Enqueue(slice.Namespace, slice.Name)
+		}
+	}
+	return nil, nil
+}
+
+// topologyChanged reports whether node's zone/region labels differ from the
+// last-seen values cached by a previous OnNodeChange call, recording the new
+// labels as a side effect.
+func (c *Controller) topologyChanged(node *corev1.Node) bool {
+	current := nodeTopology{zone: node.Labels[zoneLabel], region: node.Labels[regionLabel]}
+
+	c.nodeTopologyMu.Lock()
+	defer c.nodeTopologyMu.Unlock()
+
+	prev, seen := c.nodeTopology[node.Name]
+	if seen && prev == current {
+		return false
+	}
+
+	if c.nodeTopology == nil {
+		c.nodeTopology = map[string]nodeTopology{}
+	}
+	c.nodeTopology[node.Name] = current
+	return true
+}
+
+// anyTopologyPolicyEnabled reports whether any NetworkFilesystem in the
+// namespace has a TopologyPolicy other than None, i.e. whether node topology
+// changes are relevant to anything the controller manages.
+func (c *Controller) anyTopologyPolicyEnabled() (bool, error) {
+	networkFSs, err := c.NetworkFSCache.List(c.namespace, labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, nfs := range networkFSs {
+		if nfs.Spec.TopologyPolicy != "" && nfs.Spec.TopologyPolicy != networkfsv1.TopologyPolicyNone {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// OnEndpointSliceChange watches EndpointSlices labeled kubernetes.io/service-name=<pvc-name>,
+// aggregates the ready addresses across every slice belonging to that service, and syncs the
+// result into the matching NetworkFilesystem status.
+func (c *Controller) OnEndpointSliceChange(_ string, slice *discoveryv1.EndpointSlice) (*discoveryv1.EndpointSlice, error) {
+	if slice == nil || slice.DeletionTimestamp != nil {
+		logrus.Infof("Skip this round because endpointslice is deleted or deleting")
+		return nil, nil
+	}
+
+	serviceName, ok := slice.Labels[serviceNameLabel]
+	if !ok || !strings.HasPrefix(serviceName, "pvc-") {
+		return nil, nil
+	}
+
+	logrus.Infof("Handling endpointslice %s change event for service %s", slice.Name, serviceName)
+	networkFS, err := c.NetworkFilsystems.Get(c.namespace, serviceName, metav1.GetOptions{})
+	if err != nil {
+		logrus.Errorf("Failed to get networkFS %s: %v", serviceName, err)
+		return nil, err
+	}
+
+	if networkFS.Spec.DesiredState != networkfsv1.NetworkFSStateEnabled {
+		logrus.Infof("Skip update with endpointslice change event because networkfilesystem %s is not enabled", networkFS.Name)
+		return nil, nil
+	}
+
+	// skip update if the service.Spec.ClusterIP is not ClusterIPNone (means the we depends on service),
+	// matching the same guard pkg/controller/endpoint applies for the legacy Endpoints watch.
+	service, err := c.serviceClient.Get(utils.LHNameSpace, serviceName, metav1.GetOptions{})
+	if err != nil {
+		logrus.Errorf("Failed to get service %s: %v", serviceName, err)
+		return nil, err
+	}
+	if service.Spec.ClusterIP != corev1.ClusterIPNone {
+		logrus.Infof("Skip update with endpointslice change event because service %s is not ClusterIPNone", service.Name)
+		return nil, nil
+	}
+
+	slices, err := c.EndpointSliceCache.List(c.namespace, labels.SelectorFromSet(labels.Set{serviceNameLabel: serviceName}))
+	if err != nil {
+		logrus.Errorf("Failed to list endpointslices for service %s: %v", serviceName, err)
+		return nil, err
+	}
+
+	provider, ok := backend.Get(networkFS.Spec.Type)
+	if !ok {
+		logrus.Warnf("No backend provider registered for type %s on networkfilesystem %s, falling back to %s", networkFS.Spec.Type, networkFS.Name, networkfsv1.NetworkFSTypeNFS)
+		provider, ok = backend.Get(networkfsv1.NetworkFSTypeNFS)
+		if !ok {
+			return nil, fmt.Errorf("no nfs backend provider registered")
+		}
+	}
+
+	readyAddrs := aggregateReadyAddresses(slices)
+
+	var perNodeEndpoints map[string]string
+	if len(readyAddrs) > 0 && networkFS.Spec.TopologyPolicy != "" && networkFS.Spec.TopologyPolicy != networkfsv1.TopologyPolicyNone {
+		nodes, err := c.NodeCache.List(labels.Everything())
+		if err != nil {
+			logrus.Errorf("Failed to list nodes for topology-aware selection on networkFS %s: %v", networkFS.Name, err)
+			return nil, err
+		}
+		perNodeEndpoints = selectPerNodeEndpoints(networkFS.Spec.TopologyPolicy, nodes, collectZoneHintedAddresses(slices))
+	}
+
+	networkFSCpy := networkFS.DeepCopy()
+	if len(readyAddrs) == 0 {
+		networkFSCpy.Status.Endpoint = ""
+		networkFSCpy.Status.Endpoints = nil
+		networkFSCpy.Status.PerNodeEndpoints = nil
+		networkFSCpy.Status.BackendEndpoint = nil
+		networkFSCpy.Status.Status = networkfsv1.EndpointStatusNotReady
+		networkFSCpy.Status.Type = provider.Type()
+		networkFSCpy.Status.State = networkfsv1.NetworkFSStateEnabling
+		conds := networkfsv1.NetworkFSCondition{
+			Type:               networkfsv1.ConditionTypeNotReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "Endpoint is not ready",
+			Message:            fmt.Sprintf("%s EndpointSlices did not contain a ready address", provider.Type()),
+		}
+		networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
+	} else {
+		oldEndpoints := networkFSCpy.Status.Endpoints
+		// Providers (e.g. nfs) resolve their endpoint from Status.Endpoints, so
+		// write the freshly-aggregated addresses before calling DiscoverEndpoint
+		// rather than leaving it to see the stale (possibly empty) status.
+		networkFSCpy.Status.Endpoints = readyAddrs
+		discovered, err := provider.DiscoverEndpoint(context.Background(), networkFSCpy)
+		if err != nil {
+			logrus.Errorf("Failed to discover %s endpoint for networkfilesystem %s: %v", provider.Type(), networkFS.Name, err)
+			return nil, err
+		}
+		if err := provider.HealthCheck(context.Background(), discovered); err != nil {
+			logrus.Errorf("Health check failed for %s endpoint on networkfilesystem %s: %v", provider.Type(), networkFS.Name, err)
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(oldEndpoints, readyAddrs) {
+			conds := networkfsv1.NetworkFSCondition{
+				Type:               networkfsv1.ConditionTypeEndpointChanged,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				Reason:             "Endpoint is changed",
+				Message:            utils.ChangedEndpointsMessage(oldEndpoints, readyAddrs),
+			}
+			networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
+		}
+		networkFSCpy.Status.Endpoint = readyAddrs[0].IP
+		networkFSCpy.Status.PerNodeEndpoints = perNodeEndpoints
+		networkFSCpy.Status.BackendEndpoint = &networkfsv1.BackendEndpoint{
+			Address: discovered.Address,
+			Port:    discovered.Port,
+			IQN:     discovered.IQN,
+			Portal:  discovered.Portal,
+		}
+		networkFSCpy.Status.Status = networkfsv1.EndpointStatusReady
+		networkFSCpy.Status.Type = provider.Type()
+		networkFSCpy.Status.State = networkfsv1.NetworkFSStateEnabling
+		conds := networkfsv1.NetworkFSCondition{
+			Type:               networkfsv1.ConditionTypeReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "Endpoint is ready",
+			Message:            fmt.Sprintf("%s EndpointSlices contain the corresponding address", provider.Type()),
+		}
+		networkFSCpy.Status.NetworkFSConds = utils.UpdateNetworkFSConds(networkFSCpy.Status.NetworkFSConds, conds)
+	}
+
+	if !reflect.DeepEqual(networkFS, networkFSCpy) {
+		if _, err := c.NetworkFilsystems.UpdateStatus(networkFSCpy); err != nil {
+			logrus.Errorf("Failed to update networkFS %s: %v", networkFS.Name, err)
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// aggregateReadyAddresses flattens every ready address across all EndpointSlices
+// belonging to a service, supporting dual-stack and slices spanning the 1000-endpoint
+// per-slice limit. EndpointSliceCache.List returns slices in map-derived, not
+// API, order, so the result is sorted by IP to keep the "primary" pick and
+// the reflect.DeepEqual change-detection in OnEndpointSliceChange stable
+// across reconciles with no real change.
+func aggregateReadyAddresses(slices []*discoveryv1.EndpointSlice) []networkfsv1.EndpointAddress {
+	var addrs []networkfsv1.EndpointAddress
+	for _, slice := range slices {
+		var port int32
+		if len(slice.Ports) > 0 && slice.Ports[0].Port != nil {
+			port = *slice.Ports[0].Port
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || !*ep.Conditions.Ready {
+				continue
+			}
+			nodeName := ""
+			if ep.NodeName != nil {
+				nodeName = *ep.NodeName
+			}
+			zone := ""
+			if ep.Zone != nil {
+				zone = *ep.Zone
+			}
+			for _, ip := range ep.Addresses {
+				addrs = append(addrs, networkfsv1.EndpointAddress{
+					IP:       ip,
+					Port:     port,
+					Ready:    true,
+					NodeName: nodeName,
+					Zone:     zone,
+				})
+			}
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].IP < addrs[j].IP })
+	return addrs
+}
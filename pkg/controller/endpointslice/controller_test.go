@@ -0,0 +1,149 @@
+package endpointslice
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+	ctlntefsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
+)
+
+func boolPtr(b bool) *bool    { return &b }
+func int32Ptr(i int32) *int32 { return &i }
+
+// fakeNetworkFSCache is a minimal ctlntefsv1.NetworkFilesystemCache backed by
+// a fixed list, enough to exercise Controller.anyTopologyPolicyEnabled.
+type fakeNetworkFSCache struct {
+	ctlntefsv1.NetworkFilesystemCache
+
+	items []*networkfsv1.NetworkFilesystem
+}
+
+func (f *fakeNetworkFSCache) List(_ string, _ labels.Selector) ([]*networkfsv1.NetworkFilesystem, error) {
+	return f.items, nil
+}
+
+func TestTopologyChanged(t *testing.T) {
+	c := &Controller{}
+	n := func(zone, region string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{zoneLabel: zone, regionLabel: region}},
+		}
+	}
+
+	if !c.topologyChanged(n("us-east-1a", "us-east-1")) {
+		t.Error("topologyChanged() = false on first sighting, want true")
+	}
+	if c.topologyChanged(n("us-east-1a", "us-east-1")) {
+		t.Error("topologyChanged() = true on an unchanged heartbeat, want false")
+	}
+	if !c.topologyChanged(n("us-east-1b", "us-east-1")) {
+		t.Error("topologyChanged() = false after a zone move, want true")
+	}
+}
+
+func TestAnyTopologyPolicyEnabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []*networkfsv1.NetworkFilesystem
+		want  bool
+	}{
+		{name: "no networkfilesystems", want: false},
+		{
+			name: "only None/unset policies",
+			items: []*networkfsv1.NetworkFilesystem{
+				{Spec: networkfsv1.NetworkFSSpec{}},
+				{Spec: networkfsv1.NetworkFSSpec{TopologyPolicy: networkfsv1.TopologyPolicyNone}},
+			},
+			want: false,
+		},
+		{
+			name: "one networkfilesystem uses a real policy",
+			items: []*networkfsv1.NetworkFilesystem{
+				{Spec: networkfsv1.NetworkFSSpec{TopologyPolicy: networkfsv1.TopologyPolicyNone}},
+				{Spec: networkfsv1.NetworkFSSpec{TopologyPolicy: networkfsv1.TopologyPolicyAuto}},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Controller{NetworkFSCache: &fakeNetworkFSCache{items: tt.items}}
+			got, err := c.anyTopologyPolicyEnabled()
+			if err != nil {
+				t.Fatalf("anyTopologyPolicyEnabled() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("anyTopologyPolicyEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateReadyAddresses(t *testing.T) {
+	node1, node2 := "node1", "node2"
+	zoneA, zoneB := "zone-a", "zone-b"
+
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			Ports: []discoveryv1.EndpointPort{{Port: int32Ptr(2049)}},
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses:  []string{"10.0.0.2"},
+					Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(true)},
+					NodeName:   &node2,
+					Zone:       &zoneB,
+				},
+				{
+					// not ready: must be dropped
+					Addresses:  []string{"10.0.0.9"},
+					Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(false)},
+				},
+			},
+		},
+		{
+			// a second slice for the same service, out of IP order, exercising
+			// the >1000-endpoints-per-slice / multi-slice aggregation path.
+			Ports: []discoveryv1.EndpointPort{{Port: int32Ptr(2049)}},
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses:  []string{"10.0.0.1"},
+					Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(true)},
+					NodeName:   &node1,
+					Zone:       &zoneA,
+				},
+			},
+		},
+	}
+
+	got := aggregateReadyAddresses(slices)
+	want := []networkfsv1.EndpointAddress{
+		{IP: "10.0.0.1", Port: 2049, Ready: true, NodeName: node1, Zone: zoneA},
+		{IP: "10.0.0.2", Port: 2049, Ready: true, NodeName: node2, Zone: zoneB},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregateReadyAddresses() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateReadyAddressesNoReadyEndpoints(t *testing.T) {
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(false)}},
+				{Addresses: []string{"10.0.0.2"}, Conditions: discoveryv1.EndpointConditions{}},
+			},
+		},
+	}
+
+	if got := aggregateReadyAddresses(slices); got != nil {
+		t.Errorf("aggregateReadyAddresses() = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,152 @@
+package endpointslice
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+const (
+	zoneLabel   = "topology.kubernetes.io/zone"
+	regionLabel = "topology.kubernetes.io/region"
+)
+
+// zoneHintedAddress pairs a ready address with the zones its EndpointSlice
+// hints steer traffic towards. It is kept internal to topology selection
+// rather than folded into networkfsv1.EndpointAddress, since hints aren't
+// meaningful once persisted onto the CR status.
+type zoneHintedAddress struct {
+	networkfsv1.EndpointAddress
+	forZones []string
+}
+
+// collectZoneHintedAddresses re-walks the slices (aggregateReadyAddresses already
+// dropped hint information) to pair each ready address with its zone hints.
+func collectZoneHintedAddresses(slices []*discoveryv1.EndpointSlice) []zoneHintedAddress {
+	var addrs []zoneHintedAddress
+	for _, slice := range slices {
+		var port int32
+		if len(slice.Ports) > 0 && slice.Ports[0].Port != nil {
+			port = *slice.Ports[0].Port
+		}
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || !*ep.Conditions.Ready {
+				continue
+			}
+			nodeName := ""
+			if ep.NodeName != nil {
+				nodeName = *ep.NodeName
+			}
+			zone := ""
+			if ep.Zone != nil {
+				zone = *ep.Zone
+			}
+			var forZones []string
+			if ep.Hints != nil {
+				for _, fz := range ep.Hints.ForZones {
+					forZones = append(forZones, fz.Name)
+				}
+			}
+			for _, ip := range ep.Addresses {
+				addrs = append(addrs, zoneHintedAddress{
+					EndpointAddress: networkfsv1.EndpointAddress{
+						IP:       ip,
+						Port:     port,
+						Ready:    true,
+						NodeName: nodeName,
+						Zone:     zone,
+					},
+					forZones: forZones,
+				})
+			}
+		}
+	}
+	return addrs
+}
+
+// selectPerNodeEndpoints ranks candidate addresses per node according to the
+// NetworkFilesystem's TopologyPolicy: PreferLocal and Auto fall back to any
+// ready address when no zone/region match exists, Strict leaves the node
+// unset rather than cross zones, and None (or unset) disables per-node
+// selection entirely.
+func selectPerNodeEndpoints(policy networkfsv1.TopologyPolicy, nodes []*corev1.Node, candidates []zoneHintedAddress) map[string]string {
+	if policy == "" || policy == networkfsv1.TopologyPolicyNone || len(candidates) == 0 {
+		return nil
+	}
+
+	// candidates is built from EndpointSliceCache.List, whose map-derived
+	// order isn't stable across reconciles. Sort by IP so pickForZone's/
+	// pickForRegion's first-match and the candidates[0] fallback below pick
+	// the same address every time absent an actual topology change.
+	candidates = append([]zoneHintedAddress(nil), candidates...)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].IP < candidates[j].IP })
+
+	perNode := map[string]string{}
+	for _, node := range nodes {
+		zone := node.Labels[zoneLabel]
+		region := node.Labels[regionLabel]
+
+		if addr, ok := pickForZone(candidates, zone); ok {
+			perNode[node.Name] = addr
+			continue
+		}
+
+		if policy == networkfsv1.TopologyPolicyStrict {
+			continue
+		}
+
+		if addr, ok := pickForRegion(candidates, region); ok {
+			perNode[node.Name] = addr
+			continue
+		}
+
+		perNode[node.Name] = candidates[0].IP
+	}
+	return perNode
+}
+
+func pickForZone(candidates []zoneHintedAddress, zone string) (string, bool) {
+	if zone == "" {
+		return "", false
+	}
+	for _, c := range candidates {
+		if c.Zone == zone {
+			return c.IP, true
+		}
+		for _, fz := range c.forZones {
+			if fz == zone {
+				return c.IP, true
+			}
+		}
+	}
+	return "", false
+}
+
+// pickForRegion falls back to a same-region address when no zone matched,
+// deriving each candidate's region since EndpointSlice hints only carry
+// zones.
+func pickForRegion(candidates []zoneHintedAddress, region string) (string, bool) {
+	if region == "" {
+		return "", false
+	}
+	for _, c := range candidates {
+		if zoneRegion(c.Zone) == region {
+			return c.IP, true
+		}
+	}
+	return "", false
+}
+
+// zoneRegion derives the region a zone belongs to by trimming its trailing
+// availability-zone suffix letters, the convention
+// topology.kubernetes.io/zone values follow on every major cloud (e.g.
+// "us-east-1a" -> "us-east-1"). Comparing this instead of a bare
+// strings.HasPrefix avoids region "us-east-1" also matching zone
+// "us-east-11a", whose region is actually "us-east-11".
+func zoneRegion(zone string) string {
+	return strings.TrimRight(zone, "abcdefghijklmnopqrstuvwxyz")
+}
@@ -0,0 +1,119 @@
+package endpointslice
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+func node(name, zone, region string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{zoneLabel: zone, regionLabel: region},
+		},
+	}
+}
+
+func TestSelectPerNodeEndpoints(t *testing.T) {
+	candidates := []zoneHintedAddress{
+		{EndpointAddress: networkfsv1.EndpointAddress{IP: "10.0.0.1", Zone: "us-east-1a"}},
+		{EndpointAddress: networkfsv1.EndpointAddress{IP: "10.0.0.2", Zone: "us-west-1a"}, forZones: []string{"us-east-1b"}},
+	}
+
+	tests := []struct {
+		name   string
+		policy networkfsv1.TopologyPolicy
+		nodes  []*corev1.Node
+		want   map[string]string
+	}{
+		{
+			name:   "none policy disables selection",
+			policy: networkfsv1.TopologyPolicyNone,
+			nodes:  []*corev1.Node{node("n1", "us-east-1a", "us-east-1")},
+			want:   nil,
+		},
+		{
+			name:   "unset policy disables selection",
+			policy: "",
+			nodes:  []*corev1.Node{node("n1", "us-east-1a", "us-east-1")},
+			want:   nil,
+		},
+		{
+			name:   "auto matches own zone",
+			policy: networkfsv1.TopologyPolicyAuto,
+			nodes:  []*corev1.Node{node("n1", "us-east-1a", "us-east-1")},
+			want:   map[string]string{"n1": "10.0.0.1"},
+		},
+		{
+			name:   "auto matches a hinted zone on a different address",
+			policy: networkfsv1.TopologyPolicyAuto,
+			nodes:  []*corev1.Node{node("n1", "us-east-1b", "us-east-1")},
+			want:   map[string]string{"n1": "10.0.0.2"},
+		},
+		{
+			name:   "auto falls back to same-region when no zone matches",
+			policy: networkfsv1.TopologyPolicyAuto,
+			nodes:  []*corev1.Node{node("n1", "us-east-1c", "us-east-1")},
+			want:   map[string]string{"n1": "10.0.0.1"},
+		},
+		{
+			name:   "auto falls back to any ready address when nothing matches",
+			policy: networkfsv1.TopologyPolicyAuto,
+			nodes:  []*corev1.Node{node("n1", "eu-central-1a", "eu-central-1")},
+			want:   map[string]string{"n1": "10.0.0.1"},
+		},
+		{
+			name:   "strict leaves the node unset rather than crossing zones",
+			policy: networkfsv1.TopologyPolicyStrict,
+			nodes:  []*corev1.Node{node("n1", "eu-central-1a", "eu-central-1")},
+			want:   map[string]string{},
+		},
+		{
+			name:   "strict still matches an exact zone",
+			policy: networkfsv1.TopologyPolicyStrict,
+			nodes:  []*corev1.Node{node("n1", "us-east-1a", "us-east-1")},
+			want:   map[string]string{"n1": "10.0.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectPerNodeEndpoints(tt.policy, tt.nodes, candidates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("selectPerNodeEndpoints() = %+v, want %+v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("selectPerNodeEndpoints()[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestPickForRegionRequiresBoundaryMatch(t *testing.T) {
+	candidates := []zoneHintedAddress{
+		{EndpointAddress: networkfsv1.EndpointAddress{IP: "10.0.0.11", Zone: "us-east-11a"}},
+	}
+
+	// region "us-east-1" must not match zone "us-east-11a", whose region is
+	// "us-east-11", even though it's a string prefix.
+	if _, ok := pickForRegion(candidates, "us-east-1"); ok {
+		t.Error("pickForRegion() matched a different region via bare prefix")
+	}
+
+	if ip, ok := pickForRegion(candidates, "us-east-11"); !ok || ip != "10.0.0.11" {
+		t.Errorf("pickForRegion() = (%q, %v), want (10.0.0.11, true)", ip, ok)
+	}
+}
+
+func TestSelectPerNodeEndpointsNoCandidates(t *testing.T) {
+	got := selectPerNodeEndpoints(networkfsv1.TopologyPolicyAuto, []*corev1.Node{node("n1", "us-east-1a", "us-east-1")}, nil)
+	if got != nil {
+		t.Errorf("selectPerNodeEndpoints() = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Package controllers aggregates every generated controller client
+// networkfs-manager depends on (wrangler's core/v1 and discovery/v1, plus
+// this repo's own harvesterhci.io/v1beta1) behind a single Factory, so main
+// only has to build one object from a rest.Config and pass it once to
+// Register calls and start.All.
+package controllers
+
+import (
+	"context"
+
+	corecontroller "github.com/rancher/wrangler/v3/pkg/generated/controllers/core"
+	ctlcorev1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/core/v1"
+	discoverycontroller "github.com/rancher/wrangler/v3/pkg/generated/controllers/discovery"
+	ctldiscoveryv1 "github.com/rancher/wrangler/v3/pkg/generated/controllers/discovery/v1"
+	"k8s.io/client-go/rest"
+
+	ctlnetworkfsv1 "github.com/harvester/networkfs-manager/pkg/generated/controllers/harvesterhci.io/v1beta1"
+)
+
+// starter is satisfied by every per-group-version factory this Factory
+// wraps, so Sync/Start can drive them uniformly without depending on the
+// exact type each package names its factory.
+type starter interface {
+	Sync(ctx context.Context) error
+	Start(ctx context.Context, threadiness int) error
+}
+
+// Factory aggregates the Core, Discovery, and NetworkFS controller clients
+// networkfs-manager registers handlers against.
+type Factory struct {
+	Core      ctlcorev1.Interface
+	Discovery ctldiscoveryv1.Interface
+	NetworkFS ctlnetworkfsv1.Interface
+
+	starters []starter
+}
+
+// NewFactoryFromConfig builds the Core, Discovery, and NetworkFS controller
+// clients networkfs-manager needs from a single rest.Config.
+func NewFactoryFromConfig(cfg *rest.Config) (*Factory, error) {
+	core, err := corecontroller.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	discovery, err := discoverycontroller.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	networkfs, err := ctlnetworkfsv1.NewFactoryFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Factory{
+		Core:      core.Core().V1(),
+		Discovery: discovery.Discovery().V1(),
+		NetworkFS: networkfs,
+		starters:  []starter{core, discovery, networkfs},
+	}, nil
+}
+
+// Sync implements start.Starter so main can pass the Factory directly to
+// start.All alongside the generated factories it wraps.
+func (f *Factory) Sync(ctx context.Context) error {
+	for _, s := range f.starters {
+		if err := s.Sync(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start implements start.Starter so main can pass the Factory directly to
+// start.All alongside the generated factories it wraps.
+func (f *Factory) Start(ctx context.Context, threadiness int) error {
+	for _, s := range f.starters {
+		if err := s.Start(ctx, threadiness); err != nil {
+			return err
+		}
+	}
+	return nil
+}
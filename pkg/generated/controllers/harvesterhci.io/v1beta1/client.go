@@ -0,0 +1,117 @@
+// Package v1beta1 is the hand-rolled counterpart of the controller-gen
+// output wrangler produces for every other resource this manager watches
+// (core/v1, discovery/v1): a typed client, informer-backed cache, and
+// OnChange registration surface for harvesterhci.io/v1beta1
+// NetworkFilesystem resources, which have no upstream client since the CRD
+// is defined by this repo rather than client-go.
+package v1beta1
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+// restClientForConfig builds a rest.Interface scoped to the
+// harvesterhci.io/v1beta1 NetworkFilesystem resource from a generic
+// rest.Config, the same way wrangler wires a typed client for every other
+// resource this manager watches.
+func restClientForConfig(cfg *rest.Config) (rest.Interface, error) {
+	config := *cfg
+	config.GroupVersion = &networkfsv1.SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.NegotiatedSerializer = serializer.NewCodecFactory(networkfsv1.Scheme).WithoutConversion()
+	if config.UserAgent == "" {
+		config.UserAgent = rest.DefaultKubernetesUserAgent()
+	}
+	return rest.RESTClientFor(&config)
+}
+
+// client is the typed REST client for NetworkFilesystem resources.
+type client struct {
+	restClient rest.Interface
+	resource   string
+}
+
+func (c *client) Get(namespace, name string, opts metav1.GetOptions) (*networkfsv1.NetworkFilesystem, error) {
+	result := &networkfsv1.NetworkFilesystem{}
+	err := c.restClient.Get().
+		Namespace(namespace).
+		Resource(c.resource).
+		Name(name).
+		VersionedParams(&opts, networkfsv1.ParameterCodec).
+		Do(context.Background()).
+		Into(result)
+	return result, err
+}
+
+func (c *client) List(namespace string, opts metav1.ListOptions) (*networkfsv1.NetworkFilesystemList, error) {
+	result := &networkfsv1.NetworkFilesystemList{}
+	err := c.restClient.Get().
+		Namespace(namespace).
+		Resource(c.resource).
+		VersionedParams(&opts, networkfsv1.ParameterCodec).
+		Do(context.Background()).
+		Into(result)
+	return result, err
+}
+
+func (c *client) Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	opts.Watch = true
+	return c.restClient.Get().
+		Namespace(namespace).
+		Resource(c.resource).
+		VersionedParams(&opts, networkfsv1.ParameterCodec).
+		Watch(context.Background())
+}
+
+func (c *client) Create(obj *networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error) {
+	result := &networkfsv1.NetworkFilesystem{}
+	err := c.restClient.Post().
+		Namespace(obj.Namespace).
+		Resource(c.resource).
+		Body(obj).
+		Do(context.Background()).
+		Into(result)
+	return result, err
+}
+
+func (c *client) Update(obj *networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error) {
+	result := &networkfsv1.NetworkFilesystem{}
+	err := c.restClient.Put().
+		Namespace(obj.Namespace).
+		Resource(c.resource).
+		Name(obj.Name).
+		Body(obj).
+		Do(context.Background()).
+		Into(result)
+	return result, err
+}
+
+func (c *client) UpdateStatus(obj *networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error) {
+	result := &networkfsv1.NetworkFilesystem{}
+	err := c.restClient.Put().
+		Namespace(obj.Namespace).
+		Resource(c.resource).
+		Name(obj.Name).
+		SubResource("status").
+		Body(obj).
+		Do(context.Background()).
+		Into(result)
+	return result, err
+}
+
+func (c *client) Delete(namespace, name string, opts metav1.DeleteOptions) error {
+	return c.restClient.Delete().
+		Namespace(namespace).
+		Resource(c.resource).
+		Name(name).
+		Body(&opts).
+		Do(context.Background()).
+		Error()
+}
@@ -0,0 +1,212 @@
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+const resyncPeriod = 30 * time.Second
+
+// NetworkFilesystemHandler is the signature OnChange callbacks implement,
+// mirroring the OnChange handlers wrangler generates for every other
+// resource this manager watches (Endpoints, EndpointSlices, Nodes). The
+// returned object is discarded, not persisted; a non-nil error requeues the
+// key with rate limiting.
+type NetworkFilesystemHandler func(key string, obj *networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error)
+
+// NetworkFilesystemController is the typed client + informer registration
+// surface the endpoint controllers are built on.
+type NetworkFilesystemController interface {
+	Get(namespace, name string, opts metav1.GetOptions) (*networkfsv1.NetworkFilesystem, error)
+	Create(*networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error)
+	Update(*networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error)
+	UpdateStatus(*networkfsv1.NetworkFilesystem) (*networkfsv1.NetworkFilesystem, error)
+	Delete(namespace, name string, opts metav1.DeleteOptions) error
+	Watch(namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	Enqueue(namespace, name string)
+	OnChange(ctx context.Context, name string, handler NetworkFilesystemHandler)
+	Cache() NetworkFilesystemCache
+}
+
+// NetworkFilesystemCache is a read-only, informer-backed view of
+// NetworkFilesystem resources.
+type NetworkFilesystemCache interface {
+	Get(namespace, name string) (*networkfsv1.NetworkFilesystem, error)
+	List(namespace string, selector labels.Selector) ([]*networkfsv1.NetworkFilesystem, error)
+}
+
+type networkFilesystemController struct {
+	*client
+
+	informer cache.SharedIndexInformer
+	queue    workqueue.RateLimitingInterface
+	handler  NetworkFilesystemHandler
+
+	startInformerOnce sync.Once
+}
+
+func newNetworkFilesystemController(restClient rest.Interface) *networkFilesystemController {
+	c := &client{restClient: restClient, resource: "networkfilesystems"}
+
+	lw := &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			return c.List(metav1.NamespaceAll, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			return c.Watch(metav1.NamespaceAll, opts)
+		},
+	}
+
+	ctl := &networkFilesystemController{
+		client: c,
+		queue:  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	ctl.informer = cache.NewSharedIndexInformer(lw, &networkfsv1.NetworkFilesystem{}, resyncPeriod, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	ctl.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { ctl.enqueueFromObj(obj) },
+		UpdateFunc: func(_, obj interface{}) { ctl.enqueueFromObj(obj) },
+		DeleteFunc: func(obj interface{}) { ctl.enqueueFromObj(obj) },
+	})
+	return ctl
+}
+
+func (c *networkFilesystemController) enqueueFromObj(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		logrus.Errorf("Failed to compute key for networkfilesystem object: %v", err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Enqueue schedules namespace/name for reconciliation, the same way
+// EndpointSliceController.Enqueue lets pkg/controller/endpointslice
+// re-trigger a slice's handler from an unrelated Node watch.
+func (c *networkFilesystemController) Enqueue(namespace, name string) {
+	c.queue.Add(namespace + "/" + name)
+}
+
+// OnChange registers handler to run once per queued key. name identifies
+// this handler in logs, matching the *HandlerName constants used when
+// registering the Endpoints and EndpointSlice controllers. Events only
+// start flowing once the owning Factory's Start is called.
+func (c *networkFilesystemController) OnChange(_ context.Context, name string, handler NetworkFilesystemHandler) {
+	if c.handler != nil {
+		logrus.Warnf("Replacing existing networkfilesystem OnChange handler with %s", name)
+	}
+	c.handler = handler
+}
+
+// syncCache starts the informer (once, even across repeated calls) and
+// blocks until its cache has filled. It is called from the owning Factory's
+// Sync, and again from start in case Start is ever called on its own.
+func (c *networkFilesystemController) syncCache(ctx context.Context) error {
+	c.startInformerOnce.Do(func() {
+		go c.informer.Run(ctx.Done())
+	})
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
+		return fmt.Errorf("failed to sync networkfilesystem informer cache")
+	}
+	return nil
+}
+
+// start runs the informer and threadiness worker goroutines draining the
+// queue, stopping when ctx is cancelled. It is called from the owning
+// Factory's Start, after every OnChange registration has happened.
+func (c *networkFilesystemController) start(ctx context.Context, threadiness int) error {
+	if err := c.syncCache(ctx); err != nil {
+		return err
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go func() {
+			for c.processNextWorkItem() {
+			}
+		}()
+	}
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+	}()
+	return nil
+}
+
+func (c *networkFilesystemController) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(key.(string)); err != nil {
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *networkFilesystemController) sync(key string) error {
+	if c.handler == nil {
+		return nil
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logrus.Errorf("Invalid networkfilesystem key %s: %v", key, err)
+		return nil
+	}
+
+	obj, err := c.Cache().Get(namespace, name)
+	if err != nil {
+		logrus.Errorf("Failed to get networkfilesystem %s from cache: %v", key, err)
+		return err
+	}
+
+	if _, err := c.handler(key, obj); err != nil {
+		return fmt.Errorf("failed to handle networkfilesystem %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *networkFilesystemController) Cache() NetworkFilesystemCache {
+	return &networkFilesystemCache{informer: c.informer}
+}
+
+type networkFilesystemCache struct {
+	informer cache.SharedIndexInformer
+}
+
+func (c *networkFilesystemCache) Get(namespace, name string) (*networkfsv1.NetworkFilesystem, error) {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("networkfilesystem.harvesterhci.io %q not found", namespace+"/"+name)
+	}
+	return obj.(*networkfsv1.NetworkFilesystem), nil
+}
+
+func (c *networkFilesystemCache) List(namespace string, selector labels.Selector) ([]*networkfsv1.NetworkFilesystem, error) {
+	var result []*networkfsv1.NetworkFilesystem
+	err := cache.ListAllByNamespace(c.informer.GetIndexer(), namespace, selector, func(obj interface{}) {
+		result = append(result, obj.(*networkfsv1.NetworkFilesystem))
+	})
+	return result, err
+}
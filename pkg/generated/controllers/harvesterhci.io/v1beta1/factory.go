@@ -0,0 +1,43 @@
+package v1beta1
+
+import (
+	"context"
+
+	"k8s.io/client-go/rest"
+)
+
+// Factory builds the harvesterhci.io/v1beta1 Interface from a rest.Config,
+// the same entry point wrangler's own generated factories expose for
+// core/v1 and discovery/v1.
+type Factory struct {
+	Interface
+
+	controller *networkFilesystemController
+}
+
+// NewFactoryFromConfig builds the typed client and informer for
+// NetworkFilesystem resources.
+func NewFactoryFromConfig(cfg *rest.Config) (*Factory, error) {
+	restClient, err := restClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctl := newNetworkFilesystemController(restClient)
+	return &Factory{
+		Interface:  &version{controller: ctl},
+		controller: ctl,
+	}, nil
+}
+
+// Sync waits for this factory's informer cache to fill before Start begins
+// processing events, mirroring wrangler's own generated factories.
+func (f *Factory) Sync(ctx context.Context) error {
+	return f.controller.syncCache(ctx)
+}
+
+// Start begins processing events for every controller this factory
+// registered an OnChange handler for.
+func (f *Factory) Start(ctx context.Context, threadiness int) error {
+	return f.controller.start(ctx, threadiness)
+}
@@ -0,0 +1,17 @@
+package v1beta1
+
+// Interface exposes every controller this group-version provides, mirroring
+// the shape of wrangler's own generated core/v1 and discovery/v1 Interface
+// types so callers thread them through identically
+// (controllers.Core.Endpoints(), controllers.NetworkFS.NetworkFilesystems()).
+type Interface interface {
+	NetworkFilesystems() NetworkFilesystemController
+}
+
+type version struct {
+	controller *networkFilesystemController
+}
+
+func (v *version) NetworkFilesystems() NetworkFilesystemController {
+	return v.controller
+}
@@ -0,0 +1,53 @@
+// Package utils holds the small pieces of bookkeeping shared by both
+// endpoint controllers (pkg/controller/endpoint and
+// pkg/controller/endpointslice) so they don't each maintain their own copy.
+package utils
+
+import (
+	"fmt"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+// LHNameSpace is the namespace Longhorn share-manager Services/Endpoints
+// (and therefore the NetworkFilesystem resources tracking them) live in.
+const LHNameSpace = "longhorn-system"
+
+// Option carries the CLI-configured namespace and node name through to the
+// controllers registered from main.
+type Option struct {
+	Namespace string
+	NodeName  string
+}
+
+// UpdateNetworkFSConds appends cond to conds, replacing any existing entry
+// of the same Type so NetworkFSConds never accumulates more than one
+// observation per condition type. Callers always stamp cond.LastTransitionTime
+// with the current time, but it's only kept when the condition's Status is
+// actually transitioning; otherwise the existing entry's timestamp is
+// preserved so an unchanged reconcile produces a byte-identical status and
+// the caller's reflect.DeepEqual check can skip the UpdateStatus call.
+func UpdateNetworkFSConds(conds []networkfsv1.NetworkFSCondition, cond networkfsv1.NetworkFSCondition) []networkfsv1.NetworkFSCondition {
+	for i, existing := range conds {
+		if existing.Type == cond.Type {
+			if existing.Status == cond.Status {
+				cond.LastTransitionTime = existing.LastTransitionTime
+			}
+			conds[i] = cond
+			return conds
+		}
+	}
+	return append(conds, cond)
+}
+
+// ChangedEndpointsMessage renders the previous endpoint list into a
+// condition message so mount clients polling NetworkFSConds can detect
+// churn without diffing Status.Endpoints themselves. Shared by both
+// pkg/controller/endpoint and pkg/controller/endpointslice, which otherwise
+// carried identical copies of this helper.
+func ChangedEndpointsMessage(previous, current []networkfsv1.EndpointAddress) string {
+	if len(previous) == 0 {
+		return fmt.Sprintf("Endpoint addresses initialized with %v", current)
+	}
+	return fmt.Sprintf("Endpoint addresses changed, previous addresses were %v", previous)
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkfsv1 "github.com/harvester/networkfs-manager/pkg/apis/harvesterhci.io/v1beta1"
+)
+
+func TestChangedEndpointsMessage(t *testing.T) {
+	current := []networkfsv1.EndpointAddress{{IP: "10.0.0.1"}}
+
+	tests := []struct {
+		name     string
+		previous []networkfsv1.EndpointAddress
+		want     string
+	}{
+		{
+			name:     "no previous addresses",
+			previous: nil,
+			want:     "Endpoint addresses initialized with [{10.0.0.1 0 false  }]",
+		},
+		{
+			name:     "previous addresses present",
+			previous: []networkfsv1.EndpointAddress{{IP: "10.0.0.2"}},
+			want:     "Endpoint addresses changed, previous addresses were [{10.0.0.2 0 false  }]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChangedEndpointsMessage(tt.previous, current); got != tt.want {
+				t.Errorf("ChangedEndpointsMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateNetworkFSConds(t *testing.T) {
+	ready := networkfsv1.NetworkFSCondition{Type: networkfsv1.ConditionTypeReady, Reason: "first"}
+	readyAgain := networkfsv1.NetworkFSCondition{Type: networkfsv1.ConditionTypeReady, Reason: "second"}
+	changed := networkfsv1.NetworkFSCondition{Type: networkfsv1.ConditionTypeEndpointChanged, Reason: "third"}
+
+	conds := UpdateNetworkFSConds(nil, ready)
+	conds = UpdateNetworkFSConds(conds, changed)
+	conds = UpdateNetworkFSConds(conds, readyAgain)
+
+	want := []networkfsv1.NetworkFSCondition{readyAgain, changed}
+	if !reflect.DeepEqual(conds, want) {
+		t.Errorf("UpdateNetworkFSConds() = %+v, want %+v", conds, want)
+	}
+}
+
+func TestUpdateNetworkFSCondsPreservesTimestampWithoutTransition(t *testing.T) {
+	firstObserved := metav1.NewTime(metav1.Now().Add(-time.Hour))
+
+	conds := UpdateNetworkFSConds(nil, networkfsv1.NetworkFSCondition{
+		Type:               networkfsv1.ConditionTypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: firstObserved,
+		Reason:             "Endpoint is ready",
+	})
+
+	// Same Status on a later reconcile: LastTransitionTime must not move,
+	// or every unchanged reconcile would look different from the last and
+	// force an UpdateStatus.
+	conds = UpdateNetworkFSConds(conds, networkfsv1.NetworkFSCondition{
+		Type:               networkfsv1.ConditionTypeReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "Endpoint is ready",
+	})
+	if !conds[0].LastTransitionTime.Equal(&firstObserved) {
+		t.Errorf("LastTransitionTime = %v, want unchanged %v", conds[0].LastTransitionTime, firstObserved)
+	}
+
+	// Status actually transitions: the new timestamp must stick.
+	bumped := metav1.Now()
+	conds = UpdateNetworkFSConds(conds, networkfsv1.NetworkFSCondition{
+		Type:               networkfsv1.ConditionTypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: bumped,
+		Reason:             "Endpoint is not ready",
+	})
+	if !conds[0].LastTransitionTime.Equal(&bumped) {
+		t.Errorf("LastTransitionTime = %v, want bumped %v", conds[0].LastTransitionTime, bumped)
+	}
+}